@@ -0,0 +1,24 @@
+// Package filelock provides small cross-platform advisory locking, used to
+// keep a second process or goroutine from opening a file for reading while
+// the shredder is overwriting and renaming it.
+package filelock
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrLocked is returned by Lock when another process (or goroutine) already
+// holds an exclusive lock on the file.
+var ErrLocked = errors.New("file is locked by another process")
+
+// Lock acquires a non-blocking, exclusive advisory lock on f. It returns
+// ErrLocked if the lock is already held elsewhere.
+func Lock(f *os.File) error {
+	return lockFile(f)
+}
+
+// Unlock releases a lock previously acquired with Lock.
+func Unlock(f *os.File) error {
+	return unlockFile(f)
+}
@@ -0,0 +1,67 @@
+package filelock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLockExcludesSecondHandle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locked.txt")
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	f1, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("failed to open first handle: %v", err)
+	}
+	defer f1.Close()
+
+	if err := Lock(f1); err != nil {
+		t.Fatalf("Lock on first handle returned error: %v", err)
+	}
+	defer Unlock(f1)
+
+	f2, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("failed to open second handle: %v", err)
+	}
+	defer f2.Close()
+
+	if err := Lock(f2); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Lock on second handle = %v, want ErrLocked", err)
+	}
+}
+
+func TestUnlockAllowsReacquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "unlocked.txt")
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	f1, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("failed to open first handle: %v", err)
+	}
+	defer f1.Close()
+
+	if err := Lock(f1); err != nil {
+		t.Fatalf("Lock returned error: %v", err)
+	}
+	if err := Unlock(f1); err != nil {
+		t.Fatalf("Unlock returned error: %v", err)
+	}
+
+	f2, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("failed to open second handle: %v", err)
+	}
+	defer f2.Close()
+
+	if err := Lock(f2); err != nil {
+		t.Fatalf("Lock after Unlock should succeed, got: %v", err)
+	}
+	defer Unlock(f2)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// ErrCancelled is returned by SecureShredFileCtx (and anything built on top
+// of it) when ctx is cancelled before shredding finishes. The file is left
+// in its partially-overwritten state; it is not renamed or removed.
+var ErrCancelled = errors.New("shredding cancelled")
+
+// defaultProgressInterval is how often ProgressEvents are emitted when
+// ShredOptions.ProgressInterval is left at zero.
+const defaultProgressInterval = 100 * time.Millisecond
+
+// ProgressEvent reports aggregate shredding progress for a single file. It's
+// throttled to at most one emission per ShredOptions.ProgressInterval rather
+// than firing on every chunk, so it's safe to log or render directly.
+type ProgressEvent struct {
+	Pass        int
+	TotalPasses int
+	BytesDone   int64
+	BytesTotal  int64
+	Throughput  float64 // bytes per second
+	ETA         time.Duration
+}
+
+// ShredReport summarizes the outcome of a SecureShredFileCtx call.
+type ShredReport struct {
+	Success    bool
+	Path       string
+	NewPath    string
+	BytesTotal int64
+	Passes     int
+	Duration   time.Duration
+}
+
+// SecureShredFileCtx shreds filePath with pattern, checking ctx between
+// chunks so the operation can be cancelled cleanly, and emitting throttled
+// ProgressEvents on opts.Progress if it's set. If ctx is cancelled before
+// the shred completes, it returns ErrCancelled and the file is left in its
+// partially-overwritten state.
+func SecureShredFileCtx(ctx context.Context, filePath string, pattern ShredPattern, opts ShredOptions) (*ShredReport, error) {
+	start := time.Now()
+
+	fileInfo, err := os.Stat(filePath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("file does not exist: %s", filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting file info for %s: %w", filePath, err)
+	}
+	fileSize := fileInfo.Size()
+	totalBytes := fileSize * int64(pattern.Passes())
+
+	var bytesDone int64
+	var currentPass int32
+
+	stopProgress, progressDone := startProgressReporter(opts, pattern.Passes(), totalBytes, &bytesDone, &currentPass, start)
+
+	onStep := func(passIndex int, bytesWritten int64) {
+		atomic.StoreInt32(&currentPass, int32(passIndex))
+		atomic.AddInt64(&bytesDone, bytesWritten)
+	}
+
+	success, newPath, shredErr := shredFileTracked(ctx, filePath, fileSize, pattern, opts.Verify, onStep)
+
+	if stopProgress != nil {
+		close(stopProgress)
+		<-progressDone
+	}
+
+	if shredErr != nil {
+		return nil, shredErr
+	}
+
+	return &ShredReport{
+		Success:    success,
+		Path:       filePath,
+		NewPath:    newPath,
+		BytesTotal: fileSize,
+		Passes:     pattern.Passes(),
+		Duration:   time.Since(start),
+	}, nil
+}
+
+// startProgressReporter, if opts.Progress is set, launches a goroutine that
+// emits a ProgressEvent on it at most once per opts.ProgressInterval (or
+// defaultProgressInterval if unset), reading the shared counters under
+// atomic access. Callers must close the returned stop channel and wait on
+// done once shredding finishes, which triggers one final emission. Returns
+// (nil, nil) if opts.Progress is nil.
+func startProgressReporter(opts ShredOptions, totalPasses int, totalBytes int64, bytesDone *int64, currentPass *int32, start time.Time) (stop chan struct{}, done chan struct{}) {
+	if opts.Progress == nil {
+		return nil, nil
+	}
+
+	interval := opts.ProgressInterval
+	if interval <= 0 {
+		interval = defaultProgressInterval
+	}
+
+	stop = make(chan struct{})
+	done = make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		emit := func() {
+			emitProgress(opts.Progress, int(atomic.LoadInt32(currentPass)), totalPasses, atomic.LoadInt64(bytesDone), totalBytes, start)
+		}
+
+		for {
+			select {
+			case <-ticker.C:
+				emit()
+			case <-stop:
+				emit()
+				return
+			}
+		}
+	}()
+
+	return stop, done
+}
+
+// emitProgress sends a single ProgressEvent on ch, dropping it instead of
+// blocking if the channel isn't being drained fast enough.
+func emitProgress(ch chan<- ProgressEvent, pass, totalPasses int, bytesDone, totalBytes int64, start time.Time) {
+	elapsed := time.Since(start).Seconds()
+	var throughput float64
+	if elapsed > 0 {
+		throughput = float64(bytesDone) / elapsed
+	}
+
+	var eta time.Duration
+	if throughput > 0 && totalBytes > bytesDone {
+		eta = time.Duration(float64(totalBytes-bytesDone)/throughput) * time.Second
+	}
+
+	event := ProgressEvent{
+		Pass:        pass,
+		TotalPasses: totalPasses,
+		BytesDone:   bytesDone,
+		BytesTotal:  totalBytes,
+		Throughput:  throughput,
+		ETA:         eta,
+	}
+
+	select {
+	case ch <- event:
+	default:
+	}
+}
+
+// percentFromEvent adapts a ProgressEvent to the legacy percentage-int
+// callback shape used by ProgressCallback.
+func percentFromEvent(ev ProgressEvent) int {
+	if ev.BytesTotal <= 0 {
+		return 0
+	}
+	return int(float64(ev.BytesDone) / float64(ev.BytesTotal) * 100)
+}
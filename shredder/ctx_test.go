@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSecureShredFileCtxReportsProgress(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.bin")
+	content := make([]byte, shredChunkSize*3)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	events := make(chan ProgressEvent, 64)
+	opts := ShredOptions{Progress: events, ProgressInterval: time.Millisecond}
+
+	report, err := SecureShredFileCtx(context.Background(), path, RandomPattern(1), opts)
+	close(events)
+	if err != nil {
+		t.Fatalf("SecureShredFileCtx returned error: %v", err)
+	}
+	if !report.Success {
+		t.Fatalf("expected success")
+	}
+
+	var sawFinal bool
+	for ev := range events {
+		if ev.BytesTotal != int64(len(content)) {
+			t.Errorf("event BytesTotal = %d, want %d", ev.BytesTotal, len(content))
+		}
+		if ev.BytesDone == ev.BytesTotal {
+			sawFinal = true
+		}
+	}
+	if !sawFinal {
+		t.Error("expected at least one progress event reporting full completion")
+	}
+}
+
+func TestSecureShredFileCtxCancellation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.bin")
+	content := make([]byte, shredChunkSize*8)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := SecureShredFileCtx(ctx, path, RandomPattern(3), ShredOptions{})
+	if !errors.Is(err, ErrCancelled) {
+		t.Fatalf("SecureShredFileCtx error = %v, want ErrCancelled", err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected original path to still exist after cancellation: %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import "fmt"
+
+// VerificationError indicates a post-write readback did not match the bytes
+// the shredder believed it had just written, which can indicate
+// wear-leveling, copy-on-write snapshots, or other storage silently
+// retaining the original data.
+type VerificationError struct {
+	Path      string
+	PassIndex int
+	Offset    int64
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("verification failed for %s at pass %d, offset %d: readback did not match what was written", e.Path, e.PassIndex, e.Offset)
+}
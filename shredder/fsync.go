@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// fsyncDir fsyncs the directory at dir so that prior renames/creates within
+// it are durable across a crash or power loss. Directory sync is a no-op on
+// Windows, where os.Open on a directory and Sync don't carry the same
+// durability guarantee.
+func fsyncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("error opening directory %s for sync: %w", dir, err)
+	}
+	defer d.Close()
+
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("error syncing directory %s: %w", dir, err)
+	}
+	return nil
+}
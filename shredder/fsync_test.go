@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFinalizeShredZeroFillsViaSiblingTemp(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret.bin")
+	content := make([]byte, shredChunkSize+1024)
+	for i := range content {
+		content[i] = 0xAB
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	rf, err := openResolved(path)
+	if err != nil {
+		t.Fatalf("openResolved returned error: %v", err)
+	}
+	defer rf.Close()
+
+	newPath, err := finalizeShred(rf)
+	if err != nil {
+		t.Fatalf("finalizeShred returned error: %v", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected original path %s to no longer exist", path)
+	}
+
+	data, err := os.ReadFile(newPath)
+	if err != nil {
+		t.Fatalf("expected renamed file to exist at %s: %v", newPath, err)
+	}
+	if len(data) != len(content) {
+		t.Fatalf("expected zero-filled file of length %d, got %d", len(content), len(data))
+	}
+	for i, b := range data {
+		if b != 0 {
+			t.Fatalf("expected all-zero content, found byte %x at offset %d", b, i)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected exactly one file left in dir (no leftover temp), found %d", len(entries))
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/frijoles524/redact-external-shredder/internal/filelock"
+)
+
+func TestSecureShredFileReturnsErrLockedWhenAlreadyLocked(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "locked.txt")
+	if err := os.WriteFile(path, []byte("sensitive"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	holder, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("failed to open holder handle: %v", err)
+	}
+	defer holder.Close()
+	if err := filelock.Lock(holder); err != nil {
+		t.Fatalf("failed to acquire holder lock: %v", err)
+	}
+	defer filelock.Unlock(holder)
+
+	_, _, err = SecureShredFile(path, 1, nil)
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("SecureShredFile error = %v, want ErrLocked", err)
+	}
+}
@@ -2,192 +2,152 @@ package main
 
 import (
 	"C"
-	"crypto/rand"
-	"encoding/base64"
-	"fmt"
-	"io"
+	"errors"
 	"log"
-	"math"
 	"os"
-	"path/filepath"
-	"strconv"
-	"time"
 )
 
 var logFile *os.File
 
 type ProgressCallback func(percentage int)
 
-func SecureShredFile(filePath string, passes int, progressCallback ProgressCallback) (bool, string, error) {
-	fileInfo, err := os.Stat(filePath)
-	if os.IsNotExist(err) {
-		return false, "", fmt.Errorf("file does not exist: %s", filePath)
-	}
-	if err != nil {
-		return false, "", fmt.Errorf("error getting file info for %s: %w", filePath, err)
-	}
+// Status codes returned by shred_try so GUI callers can distinguish "locked
+// by another process" from other failures without parsing log output.
+const (
+	shredStatusOK = iota
+	shredStatusLocked
+	shredStatusError
+)
+
+//export shred_try
+func shred_try(path *C.char, count C.int) C.int {
+	filePath := C.GoString(path)
+	passes := int(count)
 
-	file, err := os.OpenFile(filePath, os.O_RDWR, 0)
+	_, _, err := SecureShredFile(filePath, passes, nil)
 	if err != nil {
-		return false, "", fmt.Errorf("no write permission or error opening file %s: %w", filePath, err)
-	}
-	defer func() {
-		if closeErr := file.Close(); closeErr != nil {
-			log.Printf("Error closing file %s: %v", filePath, closeErr)
+		if errors.Is(err, ErrLocked) {
+			log.Printf("File %s is locked by another process, skipping.", filePath)
+			return C.int(shredStatusLocked)
 		}
-	}()
-
-	fileSize := fileInfo.Size()
-	chunkSize := int64(64 * 1024) // 64 KB
-
-	// Calculate number of chunks. Ensure at least one chunk for empty files.
-	numChunks := int64(1)
-	if fileSize > 0 {
-		numChunks = int64(math.Ceil(float64(fileSize) / float64(chunkSize)))
+		log.Printf("File shredding failed for %s: %v", filePath, err)
+		return C.int(shredStatusError)
 	}
 
-	totalSteps := int64(passes)*numChunks + numChunks
-	currentStep := int64(0)
+	return C.int(shredStatusOK)
+}
 
-	for i := 0; i < passes; i++ {
-		_, err = file.Seek(0, io.SeekStart)
-		if err != nil {
-			return false, "", fmt.Errorf("error seeking file %s: %w", filePath, err)
-		}
+//export shred
+func shred(path *C.char, count C.int, resolveMode *C.char) {
+	filePath := C.GoString(path)
+	passes := int(count)
 
-		for j := int64(0); j < numChunks; j++ {
-			bufferSize := chunkSize
-			if j == numChunks-1 && fileSize%chunkSize != 0 {
-				bufferSize = fileSize % chunkSize
-			}
-			if bufferSize == 0 { // Handle case where file size is 0 or perfectly divisible
-				bufferSize = chunkSize
-			}
-
-			data := make([]byte, bufferSize)
-			_, err := rand.Read(data)
-			if err != nil { // redundant lol
-				return false, "", fmt.Errorf("error generating random data for %s: %w", filePath, err)
-			}
-
-			_, err = file.Write(data)
-			if err != nil {
-				return false, "", fmt.Errorf("error writing random data to %s: %w", filePath, err)
-			}
-			err = file.Sync() // Ensure data is written to disk
-			if err != nil {
-				return false, "", fmt.Errorf("error syncing file %s after writing random data: %w", filePath, err)
-			}
-
-			currentStep++
-			if progressCallback != nil {
-				progressCallback(int(float64(currentStep) / float64(totalSteps) * 100))
-			}
+	if mode := C.GoString(resolveMode); mode != "" {
+		if err := SetResolveMode(mode); err != nil {
+			log.Fatalf("Invalid resolve mode: %v", err)
 		}
 	}
 
-	_, err = file.Seek(0, io.SeekStart)
-	if err != nil {
-		return false, "", fmt.Errorf("error seeking file %s before zeroing: %w", filePath, err)
+	progressCallback := func(percentage int) {
+		log.Printf("\rShredding progress: %d%%", percentage)
 	}
 
-	zeroBuffer := make([]byte, chunkSize)
-	for j := int64(0); j < numChunks; j++ {
-		bufferSize := chunkSize
-		if j == numChunks-1 && fileSize%chunkSize != 0 {
-			bufferSize = fileSize % chunkSize
-		}
-		if bufferSize == 0 {
-			bufferSize = chunkSize
-		}
+	log.Println("\nStarting file shredding...")
+	success, newPath, shredErr := SecureShredFile(filePath, passes, progressCallback)
+	log.Println()
 
-		_, err = file.Write(zeroBuffer[:bufferSize])
-		if err != nil {
-			return false, "", fmt.Errorf("error writing zeros to %s: %w", filePath, err)
-		}
-		err = file.Sync()
-		if err != nil {
-			return false, "", fmt.Errorf("error syncing file %s after writing zeros: %w", filePath, err)
-		}
+	if shredErr != nil {
+		log.Fatalf("File shredding failed: %v", shredErr)
+	}
 
-		currentStep++
-		if progressCallback != nil {
-			progressCallback(int(float64(currentStep) / float64(totalSteps) * 100))
+	if success {
+		log.Printf("File shredded and removed successfully! Original: %s (was renamed to %s)\n", filePath, newPath)
+		if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+			log.Printf("Warning: Original file '%s' still exists after shredding.\n", filePath)
+		} else {
+			log.Printf("Original file '%s' no longer exists.\n", filePath)
 		}
+	} else {
+		log.Printf("File shredding failed for %s. New path: %s\n", filePath, newPath)
 	}
+}
 
-	if closeErr := file.Close(); closeErr != nil {
-		return false, "", fmt.Errorf("error closing file %s before final operations: %w", filePath, closeErr)
-	}
+//export shred_with_pattern
+func shred_with_pattern(path *C.char, patternName *C.char, resolveMode *C.char) {
+	filePath := C.GoString(path)
 
-	err = os.Truncate(filePath, 0)
-	if err != nil {
-		return false, "", fmt.Errorf("error truncating file %s: %w", filePath, err)
+	if mode := C.GoString(resolveMode); mode != "" {
+		if err := SetResolveMode(mode); err != nil {
+			log.Fatalf("Invalid resolve mode: %v", err)
+		}
 	}
-	// Note: os.Truncate implicitly syncs, but some systems might benefit from explicit sync on parent directory
 
-	dir := filepath.Dir(filePath)
-	randomBytes := make([]byte, 32)
-	_, err = rand.Read(randomBytes)
+	pattern, err := PatternByName(C.GoString(patternName))
 	if err != nil {
-		return false, "", fmt.Errorf("error generating random bytes for new file name: %w", err)
+		log.Fatalf("Unknown shred pattern: %v", err)
 	}
-	randomSuffix := base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString(randomBytes)
-
-	timestampStr := strconv.FormatInt(time.Now().Unix(), 10)
-	timestampEncoded := base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString([]byte(timestampStr))
-
-	newFileName := fmt.Sprintf("%s_%s", timestampEncoded, randomSuffix)
-	newPath := filepath.Join(dir, newFileName)
 
-	err = os.Rename(filePath, newPath)
-	if err != nil {
-		return false, "", fmt.Errorf("error renaming file from %s to %s: %w", filePath, newPath, err)
+	progressCallback := func(percentage int) {
+		log.Printf("\rShredding progress: %d%%", percentage)
 	}
 
-	log.Printf("Redacted: %s -> Renamed to: %s", filePath, newPath)
+	log.Printf("\nStarting file shredding with pattern %s...\n", pattern.Name())
+	success, newPath, shredErr := SecureShredFileWithPattern(filePath, pattern, ShredOptions{Verify: true}, progressCallback)
+	log.Println()
 
-	if progressCallback != nil {
-		progressCallback(100)
+	if shredErr != nil {
+		log.Fatalf("File shredding failed: %v", shredErr)
 	}
 
-	return true, newPath, nil
+	if success {
+		log.Printf("File shredded and removed successfully with pattern %s! Original: %s (was renamed to %s)\n", pattern.Name(), filePath, newPath)
+	} else {
+		log.Printf("File shredding failed for %s.\n", filePath)
+	}
 }
 
-//export shred
-func shred(path *C.char, count C.int) {
-	filePath := C.GoString(path)
+//export shred_path
+func shred_path(path *C.char, count C.int, workers C.int, followSymlinks C.int, skipHidden C.int, maxDepth C.int, resolveMode *C.char) {
+	rootPath := C.GoString(path)
 	passes := int(count)
 
+	if mode := C.GoString(resolveMode); mode != "" {
+		if err := SetResolveMode(mode); err != nil {
+			log.Fatalf("Invalid resolve mode: %v", err)
+		}
+	}
+
+	opts := WalkOptions{
+		FollowSymlinks: followSymlinks != 0,
+		SkipHidden:     skipHidden != 0,
+		MaxDepth:       int(maxDepth),
+		Workers:        int(workers),
+	}
+
 	progressCallback := func(percentage int) {
 		log.Printf("\rShredding progress: %d%%", percentage)
 	}
 
-	log.Println("\nStarting file shredding...")
-	success, newPath, shredErr := SecureShredFile(filePath, passes, progressCallback)
+	log.Println("\nStarting recursive shredding...")
+	success, results, err := SecureShredPath(rootPath, passes, opts, progressCallback)
 	log.Println()
 
-	if shredErr != nil {
-		log.Fatalf("File shredding failed: %v", shredErr)
+	if err != nil {
+		log.Fatalf("Path shredding failed: %v", err)
 	}
 
-	if success {
-		log.Printf("File shredded successfully! Original: %s, Renamed to: %s\n", filePath, newPath)
-		if _, err := os.Stat(filePath); !os.IsNotExist(err) {
-			log.Printf("Warning: Original file '%s' still exists after shredding.\n", filePath)
-		} else {
-			log.Printf("Original file '%s' no longer exists.\n", filePath)
+	for _, r := range results {
+		if r.Err != nil {
+			log.Printf("Failed to shred %s: %v", r.Path, r.Err)
+			continue
 		}
-	} else {
-		log.Printf("File shredding failed for %s. New path: %s\n", filePath, newPath)
+		log.Printf("Shredded and removed %s (was renamed to %s)", r.Path, r.NewPath)
 	}
 
 	if success {
-		if err := os.Remove(newPath); err != nil {
-			log.Printf("Error removing renamed file %s: %v", newPath, err)
-		} else {
-			log.Printf("Cleaned up renamed file '%s'.\n", newPath)
-		}
+		log.Printf("Recursive shredding of %s completed successfully.\n", rootPath)
+	} else {
+		log.Printf("Recursive shredding of %s completed with errors.\n", rootPath)
 	}
 }
 
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// ShredPattern defines a multi-pass overwrite scheme: how many passes to
+// perform and what bytes to write on each one. A trailing zero-fill pass
+// always runs after the pattern's own passes, regardless of which pattern
+// is selected.
+type ShredPattern interface {
+	// Name identifies the pattern for logging and pattern-name lookup.
+	Name() string
+	// Passes returns the number of overwrite passes this pattern performs.
+	Passes() int
+	// FillBuffer fills buf with the bytes for the given zero-indexed pass,
+	// reading randomness from rng when the pass calls for random data.
+	// offset is buf's starting position within the pass, in bytes, so
+	// patterns whose fill repeats with a period that doesn't evenly divide
+	// the chunk size can keep their phase continuous across chunk
+	// boundaries.
+	FillBuffer(passIndex int, buf []byte, rng io.Reader, offset int64) error
+}
+
+// ShredOptions controls optional behavior of the pattern-driven shred
+// engine, independent of which ShredPattern is selected.
+type ShredOptions struct {
+	// Verify reads back every chunk after it's written and confirms it
+	// matches what was just written, surfacing a VerificationError if the
+	// underlying storage silently returned different bytes.
+	Verify bool
+	// Progress, if non-nil, receives throttled ProgressEvents as
+	// SecureShredFileCtx works through the file. Events are dropped rather
+	// than blocking if the channel isn't drained fast enough.
+	Progress chan<- ProgressEvent
+	// ProgressInterval caps how often Progress receives an event. Zero
+	// means defaultProgressInterval.
+	ProgressInterval time.Duration
+}
+
+// randomPattern overwrites every pass with crypto/rand bytes. This is the
+// shredder's original (and default) behavior.
+type randomPattern struct {
+	passes int
+}
+
+// RandomPattern returns a ShredPattern that overwrites with random data for
+// the given number of passes.
+func RandomPattern(passes int) ShredPattern {
+	return randomPattern{passes: passes}
+}
+
+func (p randomPattern) Name() string { return "random" }
+func (p randomPattern) Passes() int  { return p.passes }
+func (p randomPattern) FillBuffer(_ int, buf []byte, rng io.Reader, _ int64) error {
+	_, err := io.ReadFull(rng, buf)
+	return err
+}
+
+// dodPattern implements DoD 5220.22-M: zeros, then ones, then random.
+type dodPattern struct{}
+
+// DoD522022M returns the classic 3-pass DoD 5220.22-M pattern: 0x00, 0xFF,
+// then random.
+func DoD522022M() ShredPattern { return dodPattern{} }
+
+func (dodPattern) Name() string { return "dod5220.22-m" }
+func (dodPattern) Passes() int  { return 3 }
+func (dodPattern) FillBuffer(passIndex int, buf []byte, rng io.Reader, _ int64) error {
+	switch passIndex {
+	case 0:
+		fillBytes(buf, 0x00)
+	case 1:
+		fillBytes(buf, 0xFF)
+	default:
+		_, err := io.ReadFull(rng, buf)
+		return err
+	}
+	return nil
+}
+
+// vsitrPattern implements the German VSITR standard: six passes alternating
+// 0x00/0xFF followed by a final random pass.
+type vsitrPattern struct{}
+
+// VSITR returns the 7-pass VSITR pattern.
+func VSITR() ShredPattern { return vsitrPattern{} }
+
+func (vsitrPattern) Name() string { return "vsitr" }
+func (vsitrPattern) Passes() int  { return 7 }
+func (vsitrPattern) FillBuffer(passIndex int, buf []byte, rng io.Reader, _ int64) error {
+	if passIndex == 6 {
+		_, err := io.ReadFull(rng, buf)
+		return err
+	}
+	fill := byte(0x00)
+	if passIndex%2 == 1 {
+		fill = 0xFF
+	}
+	fillBytes(buf, fill)
+	return nil
+}
+
+// gutmannFixedPatterns holds the 27 fixed 3-byte patterns written during
+// passes 5-31 of the Gutmann method, in order.
+var gutmannFixedPatterns = [][3]byte{
+	{0x55, 0x55, 0x55},
+	{0xAA, 0xAA, 0xAA},
+	{0x92, 0x49, 0x24},
+	{0x49, 0x24, 0x92},
+	{0x24, 0x92, 0x49},
+	{0x00, 0x00, 0x00},
+	{0x11, 0x11, 0x11},
+	{0x22, 0x22, 0x22},
+	{0x33, 0x33, 0x33},
+	{0x44, 0x44, 0x44},
+	{0x55, 0x55, 0x55},
+	{0x66, 0x66, 0x66},
+	{0x77, 0x77, 0x77},
+	{0x88, 0x88, 0x88},
+	{0x99, 0x99, 0x99},
+	{0xAA, 0xAA, 0xAA},
+	{0xBB, 0xBB, 0xBB},
+	{0xCC, 0xCC, 0xCC},
+	{0xDD, 0xDD, 0xDD},
+	{0xEE, 0xEE, 0xEE},
+	{0xFF, 0xFF, 0xFF},
+	{0x92, 0x49, 0x24},
+	{0x49, 0x24, 0x92},
+	{0x24, 0x92, 0x49},
+	{0x6D, 0xB6, 0xDB},
+	{0xB6, 0xDB, 0x6D},
+	{0xDB, 0x6D, 0xB6},
+}
+
+// gutmannPattern implements Peter Gutmann's 35-pass method: 4 random passes,
+// the 27 fixed patterns above, then 4 more random passes.
+type gutmannPattern struct{}
+
+// Gutmann returns the 35-pass Gutmann pattern.
+func Gutmann() ShredPattern { return gutmannPattern{} }
+
+func (gutmannPattern) Name() string { return "gutmann" }
+func (gutmannPattern) Passes() int  { return 35 }
+func (gutmannPattern) FillBuffer(passIndex int, buf []byte, rng io.Reader, offset int64) error {
+	if passIndex < 4 || passIndex >= 31 {
+		_, err := io.ReadFull(rng, buf)
+		return err
+	}
+	p := gutmannFixedPatterns[passIndex-4]
+	phase := int(offset % 3)
+	for i := range buf {
+		buf[i] = p[(phase+i)%3]
+	}
+	return nil
+}
+
+func fillBytes(buf []byte, b byte) {
+	for i := range buf {
+		buf[i] = b
+	}
+}
+
+// PatternByName looks up a built-in ShredPattern by its Name(). An empty
+// name selects the default 3-pass random pattern.
+func PatternByName(name string) (ShredPattern, error) {
+	switch name {
+	case "":
+		return RandomPattern(3), nil
+	case "random":
+		return RandomPattern(3), nil
+	case "dod5220.22-m", "dod":
+		return DoD522022M(), nil
+	case "vsitr":
+		return VSITR(), nil
+	case "gutmann":
+		return Gutmann(), nil
+	default:
+		return nil, fmt.Errorf("unknown shred pattern: %q", name)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDoD522022MPattern(t *testing.T) {
+	p := DoD522022M()
+	if p.Passes() != 3 {
+		t.Fatalf("expected 3 passes, got %d", p.Passes())
+	}
+
+	buf := make([]byte, 8)
+
+	if err := p.FillBuffer(0, buf, rand.Reader, 0); err != nil {
+		t.Fatalf("FillBuffer pass 0 returned error: %v", err)
+	}
+	if !bytes.Equal(buf, bytes.Repeat([]byte{0x00}, 8)) {
+		t.Errorf("pass 0 expected all zeros, got %x", buf)
+	}
+
+	if err := p.FillBuffer(1, buf, rand.Reader, 0); err != nil {
+		t.Fatalf("FillBuffer pass 1 returned error: %v", err)
+	}
+	if !bytes.Equal(buf, bytes.Repeat([]byte{0xFF}, 8)) {
+		t.Errorf("pass 1 expected all 0xFF, got %x", buf)
+	}
+}
+
+func TestVSITRPatternPassCount(t *testing.T) {
+	p := VSITR()
+	if p.Passes() != 7 {
+		t.Fatalf("expected 7 passes, got %d", p.Passes())
+	}
+
+	buf := make([]byte, 4)
+	if err := p.FillBuffer(0, buf, rand.Reader, 0); err != nil {
+		t.Fatalf("FillBuffer returned error: %v", err)
+	}
+	if !bytes.Equal(buf, []byte{0x00, 0x00, 0x00, 0x00}) {
+		t.Errorf("pass 0 expected all zeros, got %x", buf)
+	}
+	if err := p.FillBuffer(1, buf, rand.Reader, 0); err != nil {
+		t.Fatalf("FillBuffer returned error: %v", err)
+	}
+	if !bytes.Equal(buf, []byte{0xFF, 0xFF, 0xFF, 0xFF}) {
+		t.Errorf("pass 1 expected all 0xFF, got %x", buf)
+	}
+}
+
+func TestGutmannPatternPassCount(t *testing.T) {
+	p := Gutmann()
+	if p.Passes() != 35 {
+		t.Fatalf("expected 35 passes, got %d", p.Passes())
+	}
+
+	buf := make([]byte, 6)
+	if err := p.FillBuffer(4, buf, rand.Reader, 0); err != nil {
+		t.Fatalf("FillBuffer returned error: %v", err)
+	}
+	if !bytes.Equal(buf, []byte{0x55, 0x55, 0x55, 0x55, 0x55, 0x55}) {
+		t.Errorf("pass 4 (first fixed pass) expected repeating 0x55, got %x", buf)
+	}
+}
+
+func TestGutmannPatternPhaseAcrossChunkBoundary(t *testing.T) {
+	p := Gutmann()
+
+	// The fixed pattern for pass 4 repeats every 3 bytes. Filling two
+	// adjacent chunks at offsets 0 and 5 should produce the same bytes as
+	// filling one contiguous 11-byte buffer at offset 0, even though 5
+	// isn't a multiple of 3.
+	whole := make([]byte, 11)
+	if err := p.FillBuffer(4, whole, rand.Reader, 0); err != nil {
+		t.Fatalf("FillBuffer returned error: %v", err)
+	}
+
+	first := make([]byte, 5)
+	if err := p.FillBuffer(4, first, rand.Reader, 0); err != nil {
+		t.Fatalf("FillBuffer returned error: %v", err)
+	}
+	second := make([]byte, 6)
+	if err := p.FillBuffer(4, second, rand.Reader, 5); err != nil {
+		t.Fatalf("FillBuffer returned error: %v", err)
+	}
+
+	if got := append(append([]byte{}, first...), second...); !bytes.Equal(got, whole) {
+		t.Errorf("chunked fill at offset 5 = %x, want contiguous fill %x", got, whole)
+	}
+}
+
+func TestPatternByName(t *testing.T) {
+	cases := map[string]string{
+		"":             "random",
+		"random":       "random",
+		"dod":          "dod5220.22-m",
+		"dod5220.22-m": "dod5220.22-m",
+		"vsitr":        "vsitr",
+		"gutmann":      "gutmann",
+	}
+	for in, wantName := range cases {
+		p, err := PatternByName(in)
+		if err != nil {
+			t.Errorf("PatternByName(%q) returned error: %v", in, err)
+			continue
+		}
+		if p.Name() != wantName {
+			t.Errorf("PatternByName(%q).Name() = %q, want %q", in, p.Name(), wantName)
+		}
+	}
+
+	if _, err := PatternByName("not-a-pattern"); err == nil {
+		t.Error("expected error for unknown pattern name")
+	}
+}
+
+func TestSecureShredFileWithPatternVerify(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret.txt")
+	if err := os.WriteFile(path, []byte("sensitive content"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	success, newPath, err := SecureShredFileWithPattern(path, DoD522022M(), ShredOptions{Verify: true}, nil)
+	if err != nil {
+		t.Fatalf("SecureShredFileWithPattern returned error: %v", err)
+	}
+	if !success {
+		t.Fatalf("expected success")
+	}
+	if _, err := os.Stat(newPath); !os.IsNotExist(err) {
+		t.Fatalf("expected renamed file %s to have been unlinked, got err=%v", newPath, err)
+	}
+}
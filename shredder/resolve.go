@@ -0,0 +1,48 @@
+package main
+
+// ResolveMode selects how shredded files are opened and linked against their
+// parent directory to resist TOCTOU symlink-swap/directory-replacement races
+// between stat and open.
+type ResolveMode int32
+
+const (
+	// ResolveAuto probes the running kernel once and picks the strongest
+	// backend it supports: openat2, then openat, then a plain OpenFile.
+	ResolveAuto ResolveMode = iota
+	// ResolveOpenat2 resolves the file with Openat2 against its parent
+	// directory fd using RESOLVE_NO_SYMLINKS|RESOLVE_BENEATH.
+	ResolveOpenat2
+	// ResolveOpenat resolves the file with Openat against its parent
+	// directory fd, without symlink/beneath enforcement.
+	ResolveOpenat
+	// ResolvePlain falls back to a plain os.OpenFile on the path.
+	ResolvePlain
+)
+
+func (m ResolveMode) String() string {
+	switch m {
+	case ResolveOpenat2:
+		return "openat2"
+	case ResolveOpenat:
+		return "openat"
+	case ResolvePlain:
+		return "plain"
+	default:
+		return "auto"
+	}
+}
+
+func parseResolveMode(mode string) (ResolveMode, bool) {
+	switch mode {
+	case "", "auto":
+		return ResolveAuto, true
+	case "openat2":
+		return ResolveOpenat2, true
+	case "openat":
+		return ResolveOpenat, true
+	case "plain":
+		return ResolvePlain, true
+	default:
+		return ResolveAuto, false
+	}
+}
@@ -0,0 +1,164 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sys/unix"
+)
+
+var (
+	resolveOverride atomic.Int32 // holds ResolveMode, ResolveAuto means "no override"
+	probeOnce       sync.Once
+	probedMode      atomic.Int32
+)
+
+// probeResolveMode determines the strongest path-resolution backend the
+// running kernel supports, probing once and caching the result so repeated
+// shreds don't re-probe per file.
+func probeResolveMode() ResolveMode {
+	probeOnce.Do(func() {
+		mode := ResolvePlain
+		if fd, err := unix.Openat2(unix.AT_FDCWD, "/", &unix.OpenHow{
+			Flags: unix.O_RDONLY | unix.O_DIRECTORY,
+		}); err == nil {
+			unix.Close(fd)
+			mode = ResolveOpenat2
+		} else if fd, err := unix.Openat(unix.AT_FDCWD, "/", unix.O_RDONLY|unix.O_DIRECTORY, 0); err == nil {
+			unix.Close(fd)
+			mode = ResolveOpenat
+		}
+		probedMode.Store(int32(mode))
+	})
+	return ResolveMode(probedMode.Load())
+}
+
+// activeResolveMode returns the effective mode: an explicit override set via
+// SetResolveMode, or the probed auto-detected mode.
+func activeResolveMode() ResolveMode {
+	if override := ResolveMode(resolveOverride.Load()); override != ResolveAuto {
+		return override
+	}
+	return probeResolveMode()
+}
+
+// SetResolveMode forces a specific path-resolution backend instead of
+// auto-probing the kernel. Pass "auto" to restore auto-detection.
+func SetResolveMode(mode string) error {
+	parsed, ok := parseResolveMode(mode)
+	if !ok {
+		return fmt.Errorf("unknown resolve mode: %q", mode)
+	}
+	resolveOverride.Store(int32(parsed))
+	return nil
+}
+
+// resolvedFile wraps an *os.File opened through a parent-directory fd so
+// rename/unlink operations can go through that same fd instead of
+// re-resolving the path from scratch, closing the TOCTOU window an attacker
+// would otherwise get by swapping the parent directory mid-shred.
+type resolvedFile struct {
+	*os.File
+	parentFD int
+	dir      string
+	base     string
+}
+
+// openResolved opens path for read/write according to the active resolve
+// mode. Under openat2 it resolves the basename against an opened parent
+// directory fd with RESOLVE_NO_SYMLINKS|RESOLVE_BENEATH so a symlink swap or
+// directory replacement between stat and open cannot redirect the write. It
+// falls back to openat and finally a plain os.OpenFile on kernels that don't
+// support openat2.
+func openResolved(path string) (*resolvedFile, error) {
+	dir := filepath.Dir(path)
+	base := filepath.Base(path)
+
+	switch activeResolveMode() {
+	case ResolveOpenat2:
+		parentFD, err := unix.Open(dir, unix.O_RDONLY|unix.O_DIRECTORY, 0)
+		if err != nil {
+			return nil, fmt.Errorf("error opening parent directory %s: %w", dir, err)
+		}
+		fd, err := unix.Openat2(parentFD, base, &unix.OpenHow{
+			Flags:   unix.O_RDWR,
+			Resolve: unix.RESOLVE_NO_SYMLINKS | unix.RESOLVE_BENEATH,
+		})
+		if err != nil {
+			unix.Close(parentFD)
+			return nil, fmt.Errorf("error resolving %s via openat2: %w", path, err)
+		}
+		return &resolvedFile{File: os.NewFile(uintptr(fd), path), parentFD: parentFD, dir: dir, base: base}, nil
+
+	case ResolveOpenat:
+		parentFD, err := unix.Open(dir, unix.O_RDONLY|unix.O_DIRECTORY, 0)
+		if err != nil {
+			return nil, fmt.Errorf("error opening parent directory %s: %w", dir, err)
+		}
+		fd, err := unix.Openat(parentFD, base, unix.O_RDWR, 0)
+		if err != nil {
+			unix.Close(parentFD)
+			return nil, fmt.Errorf("error resolving %s via openat: %w", path, err)
+		}
+		return &resolvedFile{File: os.NewFile(uintptr(fd), path), parentFD: parentFD, dir: dir, base: base}, nil
+
+	default:
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			return nil, err
+		}
+		return &resolvedFile{File: f, parentFD: -1, dir: dir, base: base}, nil
+	}
+}
+
+// Close releases the file and, if one was opened to resolve it, the parent
+// directory fd too.
+func (r *resolvedFile) Close() error {
+	err := r.File.Close()
+	if r.parentFD >= 0 {
+		if closeErr := unix.Close(r.parentFD); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// renameAt renames the resolved file to newBase within the same directory,
+// via Renameat against the cached parent fd when available.
+func (r *resolvedFile) renameAt(newBase string) (string, error) {
+	newPath := filepath.Join(r.dir, newBase)
+	if r.parentFD >= 0 {
+		if err := unix.Renameat(r.parentFD, r.base, r.parentFD, newBase); err != nil {
+			return "", fmt.Errorf("error renaming %s to %s: %w", r.base, newBase, err)
+		}
+		return newPath, nil
+	}
+	if err := os.Rename(filepath.Join(r.dir, r.base), newPath); err != nil {
+		return "", err
+	}
+	return newPath, nil
+}
+
+// unlinkAt removes baseName from the resolved file's directory via Unlinkat
+// against the cached parent fd when available.
+func (r *resolvedFile) unlinkAt(baseName string) error {
+	if r.parentFD >= 0 {
+		return unix.Unlinkat(r.parentFD, baseName, 0)
+	}
+	return os.Remove(filepath.Join(r.dir, baseName))
+}
+
+// replaceBasename renames oldBase to newBase within the resolved file's
+// directory via Renameat against the cached parent fd when available,
+// atomically replacing whatever currently exists at newBase.
+func (r *resolvedFile) replaceBasename(oldBase, newBase string) error {
+	if r.parentFD >= 0 {
+		return unix.Renameat(r.parentFD, oldBase, r.parentFD, newBase)
+	}
+	return os.Rename(filepath.Join(r.dir, oldBase), filepath.Join(r.dir, newBase))
+}
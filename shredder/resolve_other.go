@@ -0,0 +1,59 @@
+//go:build !linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// resolvedFile is the non-Linux stand-in for the openat2-backed resolver:
+// it carries enough information to mimic the same rename/unlink API, but
+// every operation goes through the plain path-based syscalls since this
+// platform has no parent-fd-relative resolution to offer.
+type resolvedFile struct {
+	*os.File
+	parentFD int
+	dir      string
+	base     string
+}
+
+// SetResolveMode only accepts "auto" and "plain" on platforms without
+// openat2 support; the Linux build exposes the real TOCTOU-hardened
+// backend selection.
+func SetResolveMode(mode string) error {
+	parsed, ok := parseResolveMode(mode)
+	if !ok || parsed == ResolveOpenat2 || parsed == ResolveOpenat {
+		return fmt.Errorf("resolve mode %q is not supported on this platform", mode)
+	}
+	return nil
+}
+
+func openResolved(path string) (*resolvedFile, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &resolvedFile{File: f, parentFD: -1, dir: filepath.Dir(path), base: filepath.Base(path)}, nil
+}
+
+func (r *resolvedFile) Close() error {
+	return r.File.Close()
+}
+
+func (r *resolvedFile) renameAt(newBase string) (string, error) {
+	newPath := filepath.Join(r.dir, newBase)
+	if err := os.Rename(filepath.Join(r.dir, r.base), newPath); err != nil {
+		return "", err
+	}
+	return newPath, nil
+}
+
+func (r *resolvedFile) unlinkAt(baseName string) error {
+	return os.Remove(filepath.Join(r.dir, baseName))
+}
+
+func (r *resolvedFile) replaceBasename(oldBase, newBase string) error {
+	return os.Rename(filepath.Join(r.dir, oldBase), filepath.Join(r.dir, newBase))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseResolveMode(t *testing.T) {
+	cases := []struct {
+		in     string
+		want   ResolveMode
+		wantOK bool
+	}{
+		{"", ResolveAuto, true},
+		{"auto", ResolveAuto, true},
+		{"openat2", ResolveOpenat2, true},
+		{"openat", ResolveOpenat, true},
+		{"plain", ResolvePlain, true},
+		{"bogus", ResolveAuto, false},
+	}
+
+	for _, c := range cases {
+		got, ok := parseResolveMode(c.in)
+		if ok != c.wantOK || (ok && got != c.want) {
+			t.Errorf("parseResolveMode(%q) = (%v, %v), want (%v, %v)", c.in, got, ok, c.want, c.wantOK)
+		}
+	}
+}
+
+func TestOpenResolvedRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	rf, err := openResolved(path)
+	if err != nil {
+		t.Fatalf("openResolved returned error: %v", err)
+	}
+	defer rf.Close()
+
+	newPath, err := finalizeShred(rf)
+	if err != nil {
+		t.Fatalf("finalizeShred returned error: %v", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected original path %s to no longer exist", path)
+	}
+	info, err := os.Stat(newPath)
+	if err != nil {
+		t.Fatalf("expected renamed file to exist at %s: %v", newPath, err)
+	}
+	if info.Size() != 5 {
+		t.Errorf("expected renamed file to keep its original size of 5 zero-filled bytes, got %d", info.Size())
+	}
+	data, err := os.ReadFile(newPath)
+	if err != nil {
+		t.Fatalf("failed to read renamed file: %v", err)
+	}
+	for i, b := range data {
+		if b != 0 {
+			t.Errorf("expected zero-filled byte at offset %d, got %d", i, b)
+		}
+	}
+}
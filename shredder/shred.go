@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/frijoles524/redact-external-shredder/internal/filelock"
+)
+
+// ErrLocked is returned when a file is already locked by another process or
+// goroutine and can't be shredded right now.
+var ErrLocked = filelock.ErrLocked
+
+const shredChunkSize = int64(64 * 1024) // 64 KB
+
+// numChunksFor returns the number of shredChunkSize chunks needed to cover
+// size bytes, with a floor of 1 so empty files still get a pass.
+func numChunksFor(size int64) int64 {
+	if size <= 0 {
+		return 1
+	}
+	return int64(math.Ceil(float64(size) / float64(shredChunkSize)))
+}
+
+func chunkBufferSize(chunkIndex, numChunks, fileSize int64) int64 {
+	bufferSize := shredChunkSize
+	if chunkIndex == numChunks-1 && fileSize%shredChunkSize != 0 {
+		bufferSize = fileSize % shredChunkSize
+	}
+	if bufferSize == 0 {
+		bufferSize = shredChunkSize
+	}
+	return bufferSize
+}
+
+// overwriteFile runs pattern's passes over file, invoking onStep after every
+// chunk written with the pass index and bytes written so callers can track
+// progress either per-file or aggregated across a tree. When verify is set,
+// each chunk is read back immediately after writing and compared against
+// what was just written. ctx is checked between chunks; if it's cancelled,
+// overwriteFile returns ErrCancelled immediately, leaving the file in
+// whatever partially-overwritten state it had reached. The final zero-fill
+// pass is handled separately by finalizeShred, which performs it as a
+// crash-safe atomic swap rather than an in-place write.
+func overwriteFile(ctx context.Context, file *os.File, fileSize int64, pattern ShredPattern, verify bool, onStep func(passIndex int, bytesWritten int64)) error {
+	numChunks := numChunksFor(fileSize)
+
+	for passIndex := 0; passIndex < pattern.Passes(); passIndex++ {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("error seeking file before pass %d: %w", passIndex, err)
+		}
+		var offset int64
+		for j := int64(0); j < numChunks; j++ {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("shredding %s: %w", file.Name(), ErrCancelled)
+			default:
+			}
+
+			bufferSize := chunkBufferSize(j, numChunks, fileSize)
+
+			data := make([]byte, bufferSize)
+			if err := pattern.FillBuffer(passIndex, data, rand.Reader, offset); err != nil {
+				return fmt.Errorf("error generating %s pattern data: %w", pattern.Name(), err)
+			}
+
+			if _, err := file.Write(data); err != nil {
+				return fmt.Errorf("error writing pattern data: %w", err)
+			}
+			if err := file.Sync(); err != nil {
+				return fmt.Errorf("error syncing file after writing pattern data: %w", err)
+			}
+
+			if verify {
+				if err := verifyChunk(file, offset, passIndex, data); err != nil {
+					return err
+				}
+			}
+
+			offset += bufferSize
+			if onStep != nil {
+				onStep(passIndex, bufferSize)
+			}
+		}
+	}
+
+	return nil
+}
+
+// verifyChunk reads back the bytes just written at offset and confirms they
+// match expected, returning a *VerificationError if the underlying storage
+// silently returned something else (e.g. wear-leveling or copy-on-write).
+func verifyChunk(file *os.File, offset int64, passIndex int, expected []byte) error {
+	readBack := make([]byte, len(expected))
+	if _, err := file.ReadAt(readBack, offset); err != nil {
+		return fmt.Errorf("error reading back chunk at offset %d for verification: %w", offset, err)
+	}
+	if !bytes.Equal(readBack, expected) {
+		return &VerificationError{Path: file.Name(), PassIndex: passIndex, Offset: offset}
+	}
+	return nil
+}
+
+// randomObfuscatedName generates a timestamp+random basename in the same
+// style used to rename shredded files and directories.
+func randomObfuscatedName() (string, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", fmt.Errorf("error generating random bytes for new name: %w", err)
+	}
+	randomSuffix := base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString(randomBytes)
+
+	timestampStr := strconv.FormatInt(time.Now().Unix(), 10)
+	timestampEncoded := base64.URLEncoding.WithPadding(base64.NoPadding).EncodeToString([]byte(timestampStr))
+
+	return fmt.Sprintf("%s_%s", timestampEncoded, randomSuffix), nil
+}
+
+// zeroFillSiblingTemp writes a zero-filled file the same size as original
+// into dir as a sibling temp file, fsyncs it, and returns its basename. It
+// never touches original itself, so a crash here leaves the not-yet-zeroed
+// original fully intact.
+func zeroFillSiblingTemp(dir string, original *os.File) (string, error) {
+	info, err := original.Stat()
+	if err != nil {
+		return "", fmt.Errorf("error statting file for zero-fill temp: %w", err)
+	}
+	size := info.Size()
+
+	tmp, err := os.CreateTemp(dir, ".shred-*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("error creating zero-fill temp file: %w", err)
+	}
+	defer tmp.Close()
+
+	zeroBuffer := make([]byte, shredChunkSize)
+	for written := int64(0); written < size; {
+		n := size - written
+		if n > shredChunkSize {
+			n = shredChunkSize
+		}
+		if _, err := tmp.Write(zeroBuffer[:n]); err != nil {
+			os.Remove(tmp.Name())
+			return "", fmt.Errorf("error writing zeros to temp file %s: %w", tmp.Name(), err)
+		}
+		written += n
+	}
+
+	if err := tmp.Sync(); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("error syncing zero-fill temp file %s: %w", tmp.Name(), err)
+	}
+
+	return filepath.Base(tmp.Name()), nil
+}
+
+// finalizeShred performs the crash-safe equivalent of "truncate then rename
+// then remove": it zero-fills a sibling temp file, fsyncs it and the parent
+// directory, atomically renames the temp over rf's original name (fsyncing
+// the directory again), and finally renames that into the same
+// obfuscated-name style used elsewhere. At every crash point the on-disk
+// state is therefore either the fully-overwritten original name or the
+// zero-filled temp - never the original plaintext. The caller is still
+// responsible for unlinking the returned path once it's done with it.
+func finalizeShred(rf *resolvedFile) (string, error) {
+	tmpBase, err := zeroFillSiblingTemp(rf.dir, rf.File)
+	if err != nil {
+		return "", err
+	}
+
+	if err := fsyncDir(rf.dir); err != nil {
+		return "", err
+	}
+
+	if err := rf.replaceBasename(tmpBase, rf.base); err != nil {
+		return "", fmt.Errorf("error replacing %s with zero-filled temp file: %w", rf.base, err)
+	}
+	if err := fsyncDir(rf.dir); err != nil {
+		return "", err
+	}
+
+	newName, err := randomObfuscatedName()
+	if err != nil {
+		return "", err
+	}
+	newPath, err := rf.renameAt(newName)
+	if err != nil {
+		return "", err
+	}
+	if err := fsyncDir(rf.dir); err != nil {
+		return "", err
+	}
+
+	return newPath, nil
+}
+
+// renameDirToObfuscatedName renames an (already empty) directory to a
+// timestamp+random name in its parent, mirroring the obfuscated naming
+// used for files.
+func renameDirToObfuscatedName(dirPath string) (string, error) {
+	newName, err := randomObfuscatedName()
+	if err != nil {
+		return "", err
+	}
+	newPath := filepath.Join(filepath.Dir(dirPath), newName)
+	if err := os.Rename(dirPath, newPath); err != nil {
+		return "", fmt.Errorf("error renaming %s to %s: %w", dirPath, newPath, err)
+	}
+	return newPath, nil
+}
+
+// shredFileTracked is the internal counterpart to SecureShredFileCtx used
+// when the caller already knows the file size and wants a per-chunk
+// callback instead of a percentage, so progress can be aggregated across
+// many files. It returns an error wrapping ErrCancelled if ctx is cancelled
+// mid-shred. The returned path is unlinked via the still-open parent-directory
+// fd before shredFileTracked returns, so by the time a caller sees it nothing
+// exists there any more - callers must not try to remove it themselves, which
+// would re-resolve the path and reopen the TOCTOU window openResolved closed.
+func shredFileTracked(ctx context.Context, filePath string, fileSize int64, pattern ShredPattern, verify bool, onStep func(passIndex int, bytesWritten int64)) (bool, string, error) {
+	rf, err := openResolved(filePath)
+	if err != nil {
+		return false, "", fmt.Errorf("no write permission or error opening file %s: %w", filePath, err)
+	}
+	defer func() {
+		if closeErr := rf.Close(); closeErr != nil {
+			log.Printf("Error closing file %s: %v", filePath, closeErr)
+		}
+	}()
+
+	if err := filelock.Lock(rf.File); err != nil {
+		if errors.Is(err, filelock.ErrLocked) {
+			return false, "", fmt.Errorf("%s: %w", filePath, ErrLocked)
+		}
+		return false, "", fmt.Errorf("error locking file %s: %w", filePath, err)
+	}
+
+	if err := overwriteFile(ctx, rf.File, fileSize, pattern, verify, onStep); err != nil {
+		return false, "", err
+	}
+
+	if err := filelock.Unlock(rf.File); err != nil {
+		return false, "", fmt.Errorf("error unlocking file %s: %w", filePath, err)
+	}
+
+	newPath, err := finalizeShred(rf)
+	if err != nil {
+		return false, "", err
+	}
+
+	if err := rf.unlinkAt(filepath.Base(newPath)); err != nil {
+		return false, newPath, fmt.Errorf("error removing shredded file %s: %w", newPath, err)
+	}
+
+	log.Printf("Redacted and removed: %s (was renamed to %s)", filePath, newPath)
+
+	return true, newPath, nil
+}
+
+// SecureShredFileWithPattern shreds filePath using the given ShredPattern,
+// optionally verifying each chunk's readback, and reports progress as a
+// percentage complete. It's a thin wrapper around SecureShredFileCtx for
+// callers that don't need context cancellation or structured progress
+// events.
+func SecureShredFileWithPattern(filePath string, pattern ShredPattern, opts ShredOptions, progressCallback ProgressCallback) (bool, string, error) {
+	if progressCallback == nil {
+		report, err := SecureShredFileCtx(context.Background(), filePath, pattern, opts)
+		if err != nil {
+			return false, "", err
+		}
+		return report.Success, report.NewPath, nil
+	}
+
+	events := make(chan ProgressEvent, 4)
+	opts.Progress = events
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for ev := range events {
+			progressCallback(percentFromEvent(ev))
+		}
+	}()
+
+	report, err := SecureShredFileCtx(context.Background(), filePath, pattern, opts)
+	close(events)
+	<-done
+
+	if err != nil {
+		return false, "", err
+	}
+
+	progressCallback(100)
+	return report.Success, report.NewPath, nil
+}
+
+// SecureShredFile shreds filePath with passes rounds of random overwrites.
+// It is a thin wrapper around SecureShredFileWithPattern for callers that
+// don't need pattern selection or verification.
+func SecureShredFile(filePath string, passes int, progressCallback ProgressCallback) (bool, string, error) {
+	return SecureShredFileWithPattern(filePath, RandomPattern(passes), ShredOptions{}, progressCallback)
+}
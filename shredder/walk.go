@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// WalkOptions controls how SecureShredPath traverses a directory tree.
+type WalkOptions struct {
+	// FollowSymlinks causes symlinks to regular files encountered during the
+	// walk to be resolved and their targets shredded rather than skipped.
+	// Symlinks to directories are not descended into either way.
+	FollowSymlinks bool
+	// SkipHidden skips files and directories whose base name starts with a dot.
+	SkipHidden bool
+	// MaxDepth limits recursion to this many directory levels below root.
+	// Zero or negative means unlimited.
+	MaxDepth int
+	// Workers is the number of files shredded concurrently. Zero or negative
+	// defaults to runtime.NumCPU().
+	Workers int
+}
+
+// ShredPathResult reports the outcome of shredding a single file within a
+// tree. NewPath is the obfuscated name the file was renamed to immediately
+// before it was unlinked, retained for logging purposes only - by the time
+// SecureShredPath returns, nothing exists at NewPath any more.
+type ShredPathResult struct {
+	Path    string
+	NewPath string
+	Err     error
+}
+
+type shredJob struct {
+	path string
+	size int64
+}
+
+// SecureShredPath walks rootPath, shredding every regular file it finds with
+// a bounded pool of concurrent workers and unlinking each one once it has
+// been overwritten, then renames and removes the now-empty directories
+// bottom-up in the same obfuscated-name style used for files.
+// progressCallback reports percentage complete across the whole tree rather
+// than per file.
+func SecureShredPath(rootPath string, passes int, opts WalkOptions, progressCallback ProgressCallback) (bool, []ShredPathResult, error) {
+	rootInfo, err := os.Stat(rootPath)
+	if os.IsNotExist(err) {
+		return false, nil, fmt.Errorf("path does not exist: %s", rootPath)
+	}
+	if err != nil {
+		return false, nil, fmt.Errorf("error getting file info for %s: %w", rootPath, err)
+	}
+	if !rootInfo.IsDir() {
+		success, newPath, shredErr := SecureShredFile(rootPath, passes, progressCallback)
+		return success, []ShredPathResult{{Path: rootPath, NewPath: newPath, Err: shredErr}}, shredErr
+	}
+
+	jobs, dirs, err := collectShredJobs(rootPath, opts)
+	if err != nil {
+		return false, nil, fmt.Errorf("error walking %s: %w", rootPath, err)
+	}
+
+	var totalBytes int64
+	for _, j := range jobs {
+		totalBytes += j.size * int64(passes)
+	}
+	if totalBytes == 0 {
+		totalBytes = 1
+	}
+
+	var bytesDone int64
+	reportProgress := func(_ int, bytesWritten int64) {
+		if progressCallback == nil {
+			return
+		}
+		done := atomic.AddInt64(&bytesDone, bytesWritten)
+		progressCallback(int(float64(done) / float64(totalBytes) * 100))
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(jobs) && len(jobs) > 0 {
+		workers = len(jobs)
+	}
+
+	results := make([]ShredPathResult, len(jobs))
+	jobIndexes := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobIndexes {
+				job := jobs[idx]
+				_, newPath, shredErr := shredFileTracked(context.Background(), job.path, job.size, RandomPattern(passes), false, reportProgress)
+				results[idx] = ShredPathResult{Path: job.path, NewPath: newPath, Err: shredErr}
+			}
+		}()
+	}
+	for idx := range jobs {
+		jobIndexes <- idx
+	}
+	close(jobIndexes)
+	wg.Wait()
+
+	allOK := true
+	for _, r := range results {
+		if r.Err != nil {
+			allOK = false
+		}
+	}
+
+	if err := removeEmptyDirsBottomUp(dirs); err != nil {
+		return false, results, err
+	}
+
+	if progressCallback != nil {
+		progressCallback(100)
+	}
+
+	return allOK, results, nil
+}
+
+// collectShredJobs walks rootPath applying opts, returning the regular files
+// to shred and the directories encountered, deepest first, for later removal.
+func collectShredJobs(rootPath string, opts WalkOptions) ([]shredJob, []string, error) {
+	var jobs []shredJob
+	var dirs []string
+	seen := make(map[string]bool)
+
+	addJob := func(path string, size int64) {
+		key := path
+		if real, err := filepath.EvalSymlinks(path); err == nil {
+			key = real
+		}
+		if abs, err := filepath.Abs(key); err == nil {
+			key = abs
+		}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		jobs = append(jobs, shredJob{path: path, size: size})
+	}
+
+	walkFn := func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path != rootPath && opts.SkipHidden && strings.HasPrefix(filepath.Base(path), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if opts.MaxDepth > 0 && depthBelow(rootPath, path) > opts.MaxDepth {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			if !opts.FollowSymlinks {
+				return nil
+			}
+			// Shred the link's real target rather than the link name: every
+			// write/rename/unlink in the shred pipeline resolves strictly
+			// (RESOLVE_NO_SYMLINKS under openat2), so opening the link path
+			// itself would fail on that backend.
+			target, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				return fmt.Errorf("error resolving symlink %s: %w", path, err)
+			}
+			resolved, statErr := os.Stat(target)
+			if statErr != nil {
+				return fmt.Errorf("error resolving symlink %s: %w", path, statErr)
+			}
+			if resolved.IsDir() || !resolved.Mode().IsRegular() {
+				return nil
+			}
+			// A target under rootPath that filepath.Walk will also visit
+			// directly gets shredded at most once, via whichever of the two
+			// jobs is added first.
+			addJob(target, resolved.Size())
+			return nil
+		}
+
+		if info.IsDir() {
+			if path != rootPath {
+				dirs = append(dirs, path)
+			}
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		addJob(path, info.Size())
+		return nil
+	}
+
+	if err := filepath.Walk(rootPath, walkFn); err != nil {
+		return nil, nil, err
+	}
+
+	// Deepest directories first so removal can proceed bottom-up.
+	sort.Slice(dirs, func(i, j int) bool {
+		return strings.Count(dirs[i], string(os.PathSeparator)) > strings.Count(dirs[j], string(os.PathSeparator))
+	})
+
+	return jobs, dirs, nil
+}
+
+func depthBelow(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return 0
+	}
+	if rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(os.PathSeparator)) + 1
+}
+
+// removeEmptyDirsBottomUp renames each directory to an obfuscated name in the
+// same style as shredded files, then removes it. dirs must be ordered
+// deepest-first so parents are empty by the time they're reached.
+func removeEmptyDirsBottomUp(dirs []string) error {
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return fmt.Errorf("error reading directory %s: %w", dir, err)
+		}
+		if len(entries) != 0 {
+			continue
+		}
+
+		newPath, err := renameDirToObfuscatedName(dir)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(newPath); err != nil {
+			return fmt.Errorf("error removing directory %s: %w", newPath, err)
+		}
+	}
+	return nil
+}
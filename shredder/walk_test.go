@@ -0,0 +1,159 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test file %s: %v", path, err)
+	}
+}
+
+func TestSecureShredPathNestedTree(t *testing.T) {
+	root := t.TempDir()
+
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatalf("failed to create nested dirs: %v", err)
+	}
+
+	writeTestFile(t, filepath.Join(root, "top.txt"), "top level")
+	writeTestFile(t, filepath.Join(root, "a", "mid.txt"), "mid level")
+	writeTestFile(t, filepath.Join(sub, "leaf.txt"), "leaf level")
+
+	success, results, err := SecureShredPath(root, 1, WalkOptions{}, nil)
+	if err != nil {
+		t.Fatalf("SecureShredPath returned error: %v", err)
+	}
+	if !success {
+		t.Fatalf("expected overall success, got failures: %+v", results)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 shredded files, got %d", len(results))
+	}
+
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("unexpected error shredding %s: %v", r.Path, r.Err)
+		}
+		if _, err := os.Stat(r.Path); !os.IsNotExist(err) {
+			t.Errorf("expected original path %s to no longer exist", r.Path)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "a")); !os.IsNotExist(err) {
+		t.Errorf("expected subdirectory 'a' to be removed bottom-up, got err=%v", err)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", root, err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected root %s to be emptied, still has %d entries", root, len(entries))
+	}
+}
+
+func TestSecureShredPathSkipsSymlinksByDefault(t *testing.T) {
+	root := t.TempDir()
+	target := filepath.Join(root, "target.txt")
+	writeTestFile(t, target, "real file")
+
+	link := filepath.Join(root, "link.txt")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	_, results, err := SecureShredPath(root, 1, WalkOptions{}, nil)
+	if err != nil {
+		t.Fatalf("SecureShredPath returned error: %v", err)
+	}
+
+	for _, r := range results {
+		if r.Path == link {
+			t.Errorf("expected symlink %s to be skipped by default", link)
+		}
+	}
+	if _, err := os.Lstat(link); err != nil {
+		t.Errorf("expected symlink to remain untouched: %v", err)
+	}
+}
+
+func TestSecureShredPathFollowSymlinks(t *testing.T) {
+	root := t.TempDir()
+	target := filepath.Join(root, "target.txt")
+	writeTestFile(t, target, "real file")
+
+	link := filepath.Join(root, "link.txt")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	success, results, err := SecureShredPath(root, 1, WalkOptions{FollowSymlinks: true}, nil)
+	if err != nil {
+		t.Fatalf("SecureShredPath returned error: %v", err)
+	}
+	if !success {
+		t.Fatalf("expected overall success, got failures: %+v", results)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected the symlink's target to be shredded exactly once, got %d results: %+v", len(results), results)
+	}
+
+	r := results[0]
+	if r.Err != nil {
+		t.Errorf("unexpected error shredding followed symlink target: %v", r.Err)
+	}
+	if r.Path != target {
+		t.Errorf("expected result path %s to be the symlink's real target %s", r.Path, target)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Errorf("expected symlink target %s to no longer exist", target)
+	}
+	if _, err := os.Lstat(link); err != nil {
+		t.Errorf("expected symlink %s itself to be left in place (now dangling): %v", link, err)
+	}
+}
+
+func TestSecureShredPathPermissionError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits behave differently on windows")
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("root bypasses file permission checks")
+	}
+
+	root := t.TempDir()
+	blocked := filepath.Join(root, "blocked.txt")
+	writeTestFile(t, blocked, "no write for you")
+	if err := os.Chmod(blocked, 0000); err != nil {
+		t.Fatalf("failed to chmod test file: %v", err)
+	}
+	defer os.Chmod(blocked, 0644)
+
+	success, results, err := SecureShredPath(root, 1, WalkOptions{}, nil)
+	if err != nil {
+		t.Fatalf("SecureShredPath returned unexpected top-level error: %v", err)
+	}
+	if success {
+		t.Fatalf("expected overall failure due to permission error")
+	}
+
+	var found bool
+	for _, r := range results {
+		if r.Path == blocked {
+			found = true
+			if r.Err == nil {
+				t.Errorf("expected permission error for %s, got nil", blocked)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected a result entry for %s", blocked)
+	}
+}